@@ -0,0 +1,259 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// InstallParams carries everything a DesktopBackend needs to install a
+// desktop entry (and, optionally, an icon) for a newly installed AppImage.
+type InstallParams struct {
+	AppID       string
+	AppName     string
+	Description string
+	Categories  string
+	Exec        string // absolute path to the installed AppImage
+	IconPath    string // source icon file, or "" for none
+}
+
+// InstallResult is where a DesktopBackend put things, for display in
+// stepComplete and for recording in the manifest.
+type InstallResult struct {
+	DesktopFilePath string
+	IconPath        string // installed icon path, or "" if none was given
+}
+
+// DesktopBackend installs, removes, and renames the desktop entry (and
+// icon) for a teabag-managed app. UserBackend and SystemBackend differ in
+// where they write and whether they need privilege escalation;
+// DryRunBackend writes nothing at all.
+type DesktopBackend interface {
+	Install(p InstallParams) (InstallResult, error)
+	Remove(desktopFilePath string) error
+	Rename(oldPath, newPath string) error
+}
+
+// backendForScope returns the DesktopBackend for a SCOPE config value,
+// defaulting to UserBackend for anything other than "system".
+func backendForScope(scope string) DesktopBackend {
+	if scope == "system" {
+		return SystemBackend{}
+	}
+	return UserBackend{}
+}
+
+// buildDesktopEntry renders a .desktop file's contents from p, referencing
+// iconName (a bare icon name, not a path) if one was installed.
+func buildDesktopEntry(p InstallParams, iconName string) string {
+	entry := fmt.Sprintf("[Desktop Entry]\nName=%s\nExec=%s\nType=Application\nCategories=%s\n",
+		p.AppName, p.Exec, p.Categories)
+
+	if p.Description != "" {
+		entry += fmt.Sprintf("Comment=%s\n", p.Description)
+	}
+	if iconName != "" {
+		entry += fmt.Sprintf("Icon=%s\n", iconName)
+	}
+	return entry
+}
+
+// UserBackend installs desktop entries and icons under $XDG_DATA_HOME, the
+// default scope: it needs no privilege escalation at all.
+type UserBackend struct{}
+
+func (UserBackend) desktopDir() (string, error) {
+	dataHome, err := xdgDataHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataHome, "applications"), nil
+}
+
+func (b UserBackend) Install(p InstallParams) (InstallResult, error) {
+	desktopDir, err := b.desktopDir()
+	if err != nil {
+		return InstallResult{}, err
+	}
+	if err := os.MkdirAll(desktopDir, 0755); err != nil {
+		return InstallResult{}, fmt.Errorf("failed to create desktop directory: %w", err)
+	}
+
+	var iconName, iconDest string
+	if p.IconPath != "" {
+		themeDir, err := userIconThemeDir()
+		if err != nil {
+			return InstallResult{}, err
+		}
+		iconName, iconDest, err = installIcon(themeDir, p.IconPath, p.AppID)
+		if err != nil {
+			return InstallResult{}, fmt.Errorf("failed to install icon: %w", err)
+		}
+	}
+
+	desktopPath := filepath.Join(desktopDir, p.AppID+".desktop")
+	if err := os.WriteFile(desktopPath, []byte(buildDesktopEntry(p, iconName)), 0644); err != nil {
+		return InstallResult{}, fmt.Errorf("failed to write desktop entry: %w", err)
+	}
+
+	refreshDesktopDatabaseUnprivileged(desktopDir)
+	return InstallResult{DesktopFilePath: desktopPath, IconPath: iconDest}, nil
+}
+
+func (UserBackend) Remove(desktopFilePath string) error {
+	if err := os.Remove(desktopFilePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove desktop file: %w", err)
+	}
+	refreshDesktopDatabaseUnprivileged(filepath.Dir(desktopFilePath))
+	return nil
+}
+
+func (UserBackend) Rename(oldPath, newPath string) error {
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return fmt.Errorf("failed to rename desktop file: %w", err)
+	}
+	refreshDesktopDatabaseUnprivileged(filepath.Dir(newPath))
+	return nil
+}
+
+const (
+	systemDesktopDir = "/usr/share/applications"
+	systemIconDir    = "/usr/share/icons/hicolor"
+)
+
+// SystemBackend installs desktop entries and icons system-wide, under
+// /usr/share. Install escalates via pkexec only: a sudo password prompt
+// wouldn't render correctly inside the bubbletea TUI, so a missing pkexec
+// is reported as an error instead of falling back. Remove and Rename run
+// outside the TUI (from cli.go's subcommands), so they can fall back to an
+// interactive sudo prompt.
+type SystemBackend struct{}
+
+func (SystemBackend) Install(p InstallParams) (InstallResult, error) {
+	if _, err := exec.LookPath("pkexec"); err != nil {
+		return InstallResult{}, fmt.Errorf("system-scope install requires pkexec; install it, or install with --user instead")
+	}
+
+	var iconName, iconDest string
+	if p.IconPath != "" {
+		relPath, err := iconRelPath(p.IconPath, p.AppID)
+		if err != nil {
+			return InstallResult{}, err
+		}
+		iconDest = filepath.Join(systemIconDir, relPath)
+		if err := exec.Command("pkexec", "install", "-Dm644", p.IconPath, iconDest).Run(); err != nil {
+			return InstallResult{}, fmt.Errorf("failed to install icon: %w", err)
+		}
+		iconName = p.AppID
+	}
+
+	desktopPath := filepath.Join(systemDesktopDir, p.AppID+".desktop")
+	tmpDesktopFile := filepath.Join(os.TempDir(), p.AppID+".desktop")
+	if err := os.WriteFile(tmpDesktopFile, []byte(buildDesktopEntry(p, iconName)), 0644); err != nil {
+		return InstallResult{}, fmt.Errorf("failed to create temp desktop entry: %w", err)
+	}
+	defer os.Remove(tmpDesktopFile)
+
+	if err := exec.Command("pkexec", "install", "-Dm644", tmpDesktopFile, desktopPath).Run(); err != nil {
+		return InstallResult{}, fmt.Errorf("failed to install desktop entry: %w", err)
+	}
+
+	if _, err := exec.LookPath("update-desktop-database"); err == nil {
+		exec.Command("pkexec", "update-desktop-database", systemDesktopDir).Run() // Ignore errors; a stale desktop database isn't fatal
+	}
+
+	return InstallResult{DesktopFilePath: desktopPath, IconPath: iconDest}, nil
+}
+
+func (SystemBackend) Remove(desktopFilePath string) error {
+	if err := runPrivileged("rm", "-f", desktopFilePath); err != nil {
+		return fmt.Errorf("failed to remove desktop file: %w", err)
+	}
+	refreshDesktopDatabasePrivileged(filepath.Dir(desktopFilePath))
+	return nil
+}
+
+func (SystemBackend) Rename(oldPath, newPath string) error {
+	if err := runPrivileged("mv", oldPath, newPath); err != nil {
+		return fmt.Errorf("failed to rename desktop file: %w", err)
+	}
+	refreshDesktopDatabasePrivileged(filepath.Dir(newPath))
+	return nil
+}
+
+// runPrivileged runs name with args as root, preferring pkexec and falling
+// back to an interactive sudo prompt. Only called outside the TUI, where
+// stdin/stdout are a normal terminal and a password prompt works fine.
+func runPrivileged(name string, args ...string) error {
+	if _, err := exec.LookPath("pkexec"); err == nil {
+		return exec.Command("pkexec", append([]string{name}, args...)...).Run()
+	}
+	cmd := exec.Command("sudo", append([]string{name}, args...)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+func refreshDesktopDatabasePrivileged(desktopDir string) {
+	if _, err := exec.LookPath("update-desktop-database"); err != nil {
+		return
+	}
+	runPrivileged("update-desktop-database", desktopDir) // Ignore errors; a stale desktop database isn't fatal
+}
+
+func refreshDesktopDatabaseUnprivileged(desktopDir string) {
+	if _, err := exec.LookPath("update-desktop-database"); err != nil {
+		return
+	}
+	exec.Command("update-desktop-database", desktopDir).Run() // Ignore errors; a stale desktop database isn't fatal
+}
+
+// DryRunBackend prints the file operations an install (or remove/rename)
+// would perform without touching the filesystem, describing paths for
+// whichever Scope it's set to mimic. Useful for tests, and for users on
+// immutable distros (NixOS, Silverblue) who want to inspect what would be
+// written before committing to a real install.
+type DryRunBackend struct {
+	Scope string // "user" or "system"; selects which real paths to describe
+}
+
+func (b DryRunBackend) dirs() (desktopDir, iconDir string) {
+	if b.Scope == "system" {
+		return systemDesktopDir, systemIconDir
+	}
+	if dataHome, err := xdgDataHome(); err == nil {
+		return filepath.Join(dataHome, "applications"), filepath.Join(dataHome, "icons", "hicolor")
+	}
+	return systemDesktopDir, systemIconDir
+}
+
+func (b DryRunBackend) Install(p InstallParams) (InstallResult, error) {
+	desktopDir, iconDir := b.dirs()
+	desktopPath := filepath.Join(desktopDir, p.AppID+".desktop")
+
+	var iconName, iconDest string
+	if p.IconPath != "" {
+		relPath, err := iconRelPath(p.IconPath, p.AppID)
+		if err != nil {
+			return InstallResult{}, err
+		}
+		iconDest = filepath.Join(iconDir, relPath)
+		iconName = p.AppID
+		fmt.Printf("[dry-run] would install icon %s -> %s\n", p.IconPath, iconDest)
+	}
+
+	fmt.Printf("[dry-run] would write %s:\n%s", desktopPath, buildDesktopEntry(p, iconName))
+	fmt.Printf("[dry-run] would refresh desktop database in %s\n", desktopDir)
+
+	return InstallResult{DesktopFilePath: desktopPath, IconPath: iconDest}, nil
+}
+
+func (DryRunBackend) Remove(desktopFilePath string) error {
+	fmt.Printf("[dry-run] would remove %s\n", desktopFilePath)
+	return nil
+}
+
+func (DryRunBackend) Rename(oldPath, newPath string) error {
+	fmt.Printf("[dry-run] would rename %s -> %s\n", oldPath, newPath)
+	return nil
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/sivepanda/teabag/internal/manifest"
+)
+
+// runSubcommand dispatches one of teabag's non-interactive subcommands.
+// handled reports whether arg was recognized as a subcommand at all; when
+// false, main falls through to the install TUI.
+func runSubcommand(arg string, rest []string) (handled bool, err error) {
+	switch arg {
+	case "list":
+		return true, runList()
+	case "remove":
+		if len(rest) != 1 {
+			return true, fmt.Errorf("usage: teabag remove <id>")
+		}
+		return true, runRemove(rest[0])
+	case "set-id":
+		if len(rest) != 2 {
+			return true, fmt.Errorf("usage: teabag set-id <from> <to>")
+		}
+		return true, runSetID(rest[0], rest[1])
+	case "info":
+		if len(rest) != 1 {
+			return true, fmt.Errorf("usage: teabag info <id>")
+		}
+		return true, runInfo(rest[0])
+	case "upgrade":
+		return true, runUpgrade(rest)
+	default:
+		return false, nil
+	}
+}
+
+// runUpgrade parses `teabag upgrade`'s flags and either runs the headless
+// check (for --dry-run / --json / --apply, e.g. from cron) or launches the
+// interactive upgradeModel TUI.
+func runUpgrade(args []string) error {
+	var dryRun, jsonOut, apply bool
+	for _, arg := range args {
+		switch arg {
+		case "--dry-run":
+			dryRun = true
+		case "--json":
+			jsonOut = true
+		case "--apply":
+			apply = true
+		default:
+			return fmt.Errorf("usage: teabag upgrade [--dry-run] [--json] [--apply]")
+		}
+	}
+	if dryRun && apply {
+		return fmt.Errorf("--dry-run and --apply are mutually exclusive")
+	}
+
+	if dryRun || jsonOut || apply {
+		return runUpgradeCheck(dryRun, jsonOut, apply)
+	}
+
+	_, err := tea.NewProgram(newUpgradeModel()).Run()
+	return err
+}
+
+func manifestPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return manifest.PathFor(filepath.Join(homeDir, configFile)), nil
+}
+
+func runList() error {
+	path, err := manifestPath()
+	if err != nil {
+		return err
+	}
+
+	mf, err := manifest.Load(path)
+	if err != nil {
+		return err
+	}
+
+	apps := mf.Sorted()
+	if len(apps) == 0 {
+		fmt.Println(infoStyle.Render("No apps installed"))
+		return nil
+	}
+
+	for _, app := range apps {
+		fmt.Printf("%s\t%s\t%s\n", app.ID, app.Name, app.AppImagePath)
+	}
+	return nil
+}
+
+func runInfo(id string) error {
+	path, err := manifestPath()
+	if err != nil {
+		return err
+	}
+
+	mf, err := manifest.Load(path)
+	if err != nil {
+		return err
+	}
+
+	app, ok := mf.Apps[id]
+	if !ok {
+		return fmt.Errorf("no installed app with id %q", id)
+	}
+
+	fmt.Printf("ID:           %s\n", app.ID)
+	fmt.Printf("Name:         %s\n", app.Name)
+	fmt.Printf("Categories:   %s\n", app.Categories)
+	fmt.Printf("AppImage:     %s\n", app.AppImagePath)
+	fmt.Printf("Desktop file: %s\n", app.DesktopFilePath)
+	if app.IconPath != "" {
+		fmt.Printf("Icon:         %s\n", app.IconPath)
+	}
+	fmt.Printf("Installed:    %s\n", app.InstalledAt.Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+func runRemove(id string) error {
+	path, err := manifestPath()
+	if err != nil {
+		return err
+	}
+
+	var removed manifest.App
+	var found bool
+	err = manifest.WithLock(path, func(mf *manifest.Manifest) error {
+		removed, found = mf.Remove(id)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no installed app with id %q", id)
+	}
+
+	if err := os.Remove(removed.AppImagePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove AppImage: %w", err)
+	}
+
+	if err := backendForScope(removed.Scope).Remove(removed.DesktopFilePath); err != nil {
+		return err
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("✓ Removed %s (%s)", removed.Name, removed.ID)))
+	return nil
+}
+
+// runSetID renames an installed app's manifest id, its desktop file, and
+// the manifest's record of that desktop file's path all under a single
+// manifest lock. If the desktop file rename fails, the id rename is rolled
+// back so the manifest never points at a path that no longer exists.
+func runSetID(from, to string) error {
+	path, err := manifestPath()
+	if err != nil {
+		return err
+	}
+
+	return manifest.WithLock(path, func(mf *manifest.Manifest) error {
+		app, err := mf.Rename(from, to)
+		if err != nil {
+			return err
+		}
+
+		newDesktopFile := filepath.Join(filepath.Dir(app.DesktopFilePath), to+".desktop")
+		if err := backendForScope(app.Scope).Rename(app.DesktopFilePath, newDesktopFile); err != nil {
+			if _, rollbackErr := mf.Rename(to, from); rollbackErr != nil {
+				return fmt.Errorf("rename failed (%w) and rollback of id failed: %v", err, rollbackErr)
+			}
+			return err
+		}
+
+		app.DesktopFilePath = newDesktopFile
+		mf.Apps[to] = app
+		return nil
+	})
+}
+
+// slugify turns an application name into a lowercase, hyphenated id
+// suitable for use as a manifest key and desktop file name.
+func slugify(name string) string {
+	var b strings.Builder
+	lastHyphen := true // avoid a leading hyphen
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			lastHyphen = false
+		case !lastHyphen:
+			b.WriteByte('-')
+			lastHyphen = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		slug = "app"
+	}
+	return slug
+}
@@ -0,0 +1,15 @@
+package main
+
+import "testing"
+
+func TestRunUpgradeRejectsDryRunAndApply(t *testing.T) {
+	if err := runUpgrade([]string{"--dry-run", "--apply"}); err == nil {
+		t.Error("expected error when --dry-run and --apply are combined")
+	}
+}
+
+func TestRunUpgradeRejectsUnknownFlag(t *testing.T) {
+	if err := runUpgrade([]string{"--bogus"}); err == nil {
+		t.Error("expected error for an unrecognized flag")
+	}
+}
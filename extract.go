@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// extractedInfo is whatever teabag could pre-fill by peeking inside an
+// AppImage's bundled .desktop file and icon, before the user is asked to
+// confirm anything.
+type extractedInfo struct {
+	appName     string
+	description string
+	categories  string
+	// iconPath points at a temp-file copy of the bundled icon (if any),
+	// kept around so install() can still read it after extraction's own
+	// working directory has been cleaned up.
+	iconPath string
+}
+
+// extractedInfoMsg is delivered once background extraction finishes.
+type extractedInfoMsg struct {
+	info extractedInfo
+	err  error
+}
+
+// extractAppImageMetadata runs the bundled .desktop/icon extraction for
+// appImagePath as a tea.Cmd. A failure here is never fatal to the install
+// flow -- it just means nothing gets pre-filled -- so the message always
+// carries whatever was found even when err is set.
+func extractAppImageMetadata(appImagePath string) tea.Cmd {
+	return func() tea.Msg {
+		info, err := runExtraction(appImagePath)
+		return extractedInfoMsg{info: info, err: err}
+	}
+}
+
+func runExtraction(appImagePath string) (extractedInfo, error) {
+	absPath, err := filepath.Abs(appImagePath)
+	if err != nil {
+		return extractedInfo{}, fmt.Errorf("failed to resolve AppImage path: %w", err)
+	}
+
+	workDir, err := os.MkdirTemp("", "teabag-extract-")
+	if err != nil {
+		return extractedInfo{}, fmt.Errorf("failed to create extraction dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	root, err := extractAppImage(absPath, workDir)
+	if err != nil {
+		return extractedInfo{}, err
+	}
+
+	var info extractedInfo
+	var iconHint string
+
+	if desktopPath, err := findFileBySuffix(root, ".desktop"); err == nil {
+		entries, err := parseDesktopEntry(desktopPath)
+		if err == nil {
+			info.appName = entries["Name"]
+			info.description = entries["Comment"]
+			info.categories = entries["Categories"]
+			iconHint = entries["Icon"]
+		}
+	}
+
+	if iconSrc := findIcon(root, iconHint); iconSrc != "" {
+		if persisted, err := persistIcon(iconSrc); err == nil {
+			info.iconPath = persisted
+		}
+	}
+
+	return info, nil
+}
+
+// extractAppImage runs `<appImagePath> --appimage-extract` with workDir as
+// the working directory, returning the resulting squashfs-root. It first
+// tries a selective extract of just the files teabag cares about, falling
+// back to extracting the whole archive when the glob extraction comes back
+// empty (older AppImages don't all support selective globs).
+func extractAppImage(appImagePath, workDir string) (string, error) {
+	squashRoot := filepath.Join(workDir, "squashfs-root")
+
+	selective := exec.Command(appImagePath, "--appimage-extract", "*.desktop", "*.png", "*.svg", ".DirIcon")
+	selective.Dir = workDir
+	if err := selective.Run(); err == nil && dirHasFiles(squashRoot) {
+		return squashRoot, nil
+	}
+
+	os.RemoveAll(squashRoot)
+
+	full := exec.Command(appImagePath, "--appimage-extract")
+	full.Dir = workDir
+	if err := full.Run(); err != nil {
+		return "", fmt.Errorf("failed to extract AppImage: %w", err)
+	}
+	if !dirHasFiles(squashRoot) {
+		return "", fmt.Errorf("extraction produced no files")
+	}
+	return squashRoot, nil
+}
+
+func dirHasFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	return err == nil && len(entries) > 0
+}
+
+// findFileBySuffix walks root for the first file whose name ends in
+// suffix (case-insensitive).
+func findFileBySuffix(root, suffix string) (string, error) {
+	var found string
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || found != "" {
+			return nil
+		}
+		if strings.HasSuffix(strings.ToLower(d.Name()), strings.ToLower(suffix)) {
+			found = path
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", fmt.Errorf("no file ending in %s found under %s", suffix, root)
+	}
+	return found, nil
+}
+
+// findIcon looks for the icon file referenced by an Icon= hint (with any of
+// the extensions AppImages commonly bundle), then falls back to .DirIcon,
+// then to the first .png or .svg it finds anywhere in root.
+func findIcon(root, iconHint string) string {
+	if iconHint != "" {
+		for _, ext := range []string{".png", ".svg"} {
+			if path, err := findFileBySuffix(root, strings.ToLower(iconHint)+ext); err == nil {
+				return path
+			}
+		}
+	}
+
+	if path, err := findFileBySuffix(root, ".diricon"); err == nil {
+		return path
+	}
+	if path, err := findFileBySuffix(root, ".png"); err == nil {
+		return path
+	}
+	if path, err := findFileBySuffix(root, ".svg"); err == nil {
+		return path
+	}
+	return ""
+}
+
+// persistIcon copies srcPath into a new temp file that will survive the
+// caller's extraction working directory being removed.
+func persistIcon(srcPath string) (string, error) {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp("", "teabag-icon-*"+filepath.Ext(srcPath))
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		os.Remove(dst.Name())
+		return "", err
+	}
+	return dst.Name(), nil
+}
+
+// parseDesktopEntry is a small ini-style parser that reads just the
+// [Desktop Entry] section of a .desktop file into a key/value map.
+func parseDesktopEntry(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := map[string]string{}
+	inSection := false
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inSection = line == "[Desktop Entry]"
+			continue
+		}
+		if !inSection {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		entries[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return entries, scanner.Err()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseDesktopEntry(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.desktop")
+	content := "[Desktop Entry]\nName=My App\nComment=Does things\nCategories=Utility;Development;\nIcon=my-app\n\n[Desktop Action Foo]\nName=Ignored\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := parseDesktopEntry(path)
+	if err != nil {
+		t.Fatalf("parseDesktopEntry failed: %v", err)
+	}
+
+	want := map[string]string{
+		"Name":       "My App",
+		"Comment":    "Does things",
+		"Categories": "Utility;Development;",
+		"Icon":       "my-app",
+	}
+	for k, v := range want {
+		if entries[k] != v {
+			t.Errorf("entries[%q] = %q, want %q", k, entries[k], v)
+		}
+	}
+	if _, ok := entries["Name"]; !ok || entries["Name"] == "Ignored" {
+		t.Errorf("expected Name from [Desktop Entry] section, not a later section")
+	}
+}
+
+func TestFindFileBySuffix(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "nested")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	wantPath := filepath.Join(sub, "app.desktop")
+	if err := os.WriteFile(wantPath, []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := findFileBySuffix(dir, ".desktop")
+	if err != nil {
+		t.Fatalf("findFileBySuffix failed: %v", err)
+	}
+	if got != wantPath {
+		t.Errorf("findFileBySuffix = %q, want %q", got, wantPath)
+	}
+
+	if _, err := findFileBySuffix(dir, ".svg"); err == nil {
+		t.Error("expected error when no matching file exists")
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// xdgDataHome returns $XDG_DATA_HOME, falling back to ~/.local/share per
+// the XDG base directory spec.
+func xdgDataHome() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".local", "share"), nil
+}
+
+// userIconThemeDir returns the root of the hicolor icon theme user-scope
+// installs use, per the XDG icon theme spec.
+func userIconThemeDir() (string, error) {
+	dataHome, err := xdgDataHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataHome, "icons", "hicolor"), nil
+}
+
+// iconRelPath returns where an icon at srcPath belongs within a hicolor
+// theme root, derived from appID: PNGs are sorted into their pixel-size
+// directory, SVGs go into scalable/apps. A bundled .DirIcon is always a
+// raster image regardless of its extension-less name, so it's treated as
+// a PNG rather than rejected as an unrecognized format.
+func iconRelPath(srcPath, appID string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(srcPath))
+	if ext == ".diricon" {
+		ext = ".png"
+	}
+
+	switch ext {
+	case ".svg":
+		return filepath.Join("scalable", "apps", appID+ext), nil
+	case ".png":
+		size, err := pngSize(srcPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read icon dimensions: %w", err)
+		}
+		return filepath.Join(fmt.Sprintf("%dx%d", size, size), "apps", appID+ext), nil
+	default:
+		return "", fmt.Errorf("unsupported icon format: %s", ext)
+	}
+}
+
+// installIcon copies the icon at srcPath into themeDir under a name
+// derived from appID, and returns that bare name (for use as a desktop
+// entry's Icon= value, so theme lookup finds it regardless of size) along
+// with the full path it was installed to.
+func installIcon(themeDir, srcPath, appID string) (name, destPath string, err error) {
+	relPath, err := iconRelPath(srcPath, appID)
+	if err != nil {
+		return "", "", err
+	}
+
+	destPath = filepath.Join(themeDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create icon directory: %w", err)
+	}
+	if err := copyFile(srcPath, destPath); err != nil {
+		return "", "", fmt.Errorf("failed to copy icon: %w", err)
+	}
+
+	return appID, destPath, nil
+}
+
+// pngSize returns the larger of a PNG's width and height, used to pick its
+// hicolor size bucket (e.g. 128x128).
+func pngSize(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	cfg, err := png.DecodeConfig(f)
+	if err != nil {
+		return 0, err
+	}
+
+	size := cfg.Width
+	if cfg.Height > size {
+		size = cfg.Height
+	}
+	return size, nil
+}
+
+func copyFile(srcPath, destPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestPNG writes a tiny valid PNG (regardless of the destination file's
+// extension) so tests can exercise format detection against content, not
+// just the file name.
+func writeTestPNG(t *testing.T, path string, size int) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create %s: %v", path, err)
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, img); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+}
+
+func TestIconRelPathDirIcon(t *testing.T) {
+	dir := t.TempDir()
+	srcPath := filepath.Join(dir, ".DirIcon")
+	writeTestPNG(t, srcPath, 64)
+
+	rel, err := iconRelPath(srcPath, "my-app")
+	if err != nil {
+		t.Fatalf("iconRelPath failed: %v", err)
+	}
+
+	want := filepath.Join("64x64", "apps", "my-app.png")
+	if rel != want {
+		t.Errorf("iconRelPath = %q, want %q", rel, want)
+	}
+}
+
+func TestInstallIconDirIcon(t *testing.T) {
+	dir := t.TempDir()
+	srcPath := filepath.Join(dir, ".DirIcon")
+	writeTestPNG(t, srcPath, 64)
+
+	themeDir := t.TempDir()
+	name, destPath, err := installIcon(themeDir, srcPath, "my-app")
+	if err != nil {
+		t.Fatalf("installIcon failed: %v", err)
+	}
+
+	if name != "my-app" {
+		t.Errorf("installIcon name = %q, want %q", name, "my-app")
+	}
+	if _, err := os.Stat(destPath); err != nil {
+		t.Errorf("installed icon missing at %s: %v", destPath, err)
+	}
+}
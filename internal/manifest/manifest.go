@@ -0,0 +1,177 @@
+// Package manifest tracks every AppImage teabag has installed, so that
+// teabag can list, inspect, rename, and remove them later instead of
+// forgetting about them the moment install() returns.
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+)
+
+// FileName is the manifest's file name; it lives next to the legacy
+// APPIMAGE_DIR config file rather than replacing it.
+const FileName = "installed.json"
+
+// App is a single installed AppImage and everything teabag wrote to the
+// system on its behalf.
+type App struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Categories      string    `json:"categories"`
+	AppImagePath    string    `json:"appimage_path"`
+	DesktopFilePath string    `json:"desktop_file_path"`
+	IconPath        string    `json:"icon_path,omitempty"`
+	Scope           string    `json:"scope"`
+	InstalledAt     time.Time `json:"installed_at"`
+}
+
+// Manifest is the on-disk record of every app teabag manages, keyed by ID.
+type Manifest struct {
+	Apps map[string]App `json:"apps"`
+}
+
+// PathFor returns the manifest path that sits alongside configPath, the
+// legacy appimage-installer.conf.
+func PathFor(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), FileName)
+}
+
+// Load reads the manifest at path, returning an empty Manifest if the file
+// doesn't exist yet.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Manifest{Apps: map[string]App{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if m.Apps == nil {
+		m.Apps = map[string]App{}
+	}
+	return &m, nil
+}
+
+// Save writes the manifest to path, creating its parent directory if
+// necessary.
+func Save(path string, m *Manifest) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace manifest: %w", err)
+	}
+	return nil
+}
+
+// WithLock loads the manifest at path under an exclusive file lock, runs fn
+// against it, and saves the result before releasing the lock. This keeps
+// concurrent `teabag` invocations (e.g. an install racing a remove) from
+// stomping on each other's writes.
+func WithLock(path string, fn func(*Manifest) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest dir: %w", err)
+	}
+
+	lockPath := path + ".lock"
+	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open manifest lock: %w", err)
+	}
+	defer lockFile.Close()
+
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("failed to lock manifest: %w", err)
+	}
+	defer syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
+
+	m, err := Load(path)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(m); err != nil {
+		return err
+	}
+
+	return Save(path, m)
+}
+
+// UniqueID returns base if it isn't already in the manifest, otherwise
+// base-2, base-3, and so on until one is free.
+func (m *Manifest) UniqueID(base string) string {
+	if _, exists := m.Apps[base]; !exists {
+		return base
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", base, i)
+		if _, exists := m.Apps[candidate]; !exists {
+			return candidate
+		}
+	}
+}
+
+// Add records app in the manifest, keyed by its ID.
+func (m *Manifest) Add(app App) {
+	if m.Apps == nil {
+		m.Apps = map[string]App{}
+	}
+	m.Apps[app.ID] = app
+}
+
+// Remove deletes the app with the given id, returning it and whether it was
+// found.
+func (m *Manifest) Remove(id string) (App, bool) {
+	app, ok := m.Apps[id]
+	if ok {
+		delete(m.Apps, id)
+	}
+	return app, ok
+}
+
+// Rename moves the app at id `from` to `to`, failing if `from` doesn't
+// exist or `to` is already taken.
+func (m *Manifest) Rename(from, to string) (App, error) {
+	app, ok := m.Apps[from]
+	if !ok {
+		return App{}, fmt.Errorf("no installed app with id %q", from)
+	}
+	if _, taken := m.Apps[to]; taken {
+		return App{}, fmt.Errorf("id %q is already in use", to)
+	}
+
+	delete(m.Apps, from)
+	app.ID = to
+	m.Apps[to] = app
+	return app, nil
+}
+
+// Sorted returns the manifest's apps sorted by ID, for stable CLI output.
+func (m *Manifest) Sorted() []App {
+	apps := make([]App, 0, len(m.Apps))
+	for _, app := range m.Apps {
+		apps = append(apps, app)
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].ID < apps[j].ID })
+	return apps
+}
@@ -0,0 +1,70 @@
+package manifest
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestUniqueID(t *testing.T) {
+	m := &Manifest{Apps: map[string]App{"foo": {}, "foo-2": {}}}
+
+	if got := m.UniqueID("bar"); got != "bar" {
+		t.Errorf("UniqueID(bar) = %q, want %q", got, "bar")
+	}
+	if got := m.UniqueID("foo"); got != "foo-3" {
+		t.Errorf("UniqueID(foo) = %q, want %q", got, "foo-3")
+	}
+}
+
+func TestAddRemoveRename(t *testing.T) {
+	m := &Manifest{Apps: map[string]App{}}
+	m.Add(App{ID: "foo", Name: "Foo"})
+
+	if _, ok := m.Apps["foo"]; !ok {
+		t.Fatal("expected foo to be added")
+	}
+
+	renamed, err := m.Rename("foo", "bar")
+	if err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	if renamed.ID != "bar" {
+		t.Errorf("renamed.ID = %q, want %q", renamed.ID, "bar")
+	}
+	if _, ok := m.Apps["foo"]; ok {
+		t.Error("expected foo to be gone after rename")
+	}
+
+	if _, err := m.Rename("foo", "baz"); err == nil {
+		t.Error("expected error renaming nonexistent id")
+	}
+
+	app, ok := m.Remove("bar")
+	if !ok || app.Name != "Foo" {
+		t.Errorf("Remove(bar) = %+v, %v", app, ok)
+	}
+	if _, ok := m.Remove("bar"); ok {
+		t.Error("expected second Remove to report not found")
+	}
+}
+
+func TestSaveLoadWithLock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "installed.json")
+
+	err := WithLock(path, func(m *Manifest) error {
+		m.Add(App{ID: "foo", Name: "Foo"})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithLock failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, ok := loaded.Apps["foo"]; !ok {
+		t.Errorf("expected foo to be persisted, got %+v", loaded.Apps)
+	}
+}
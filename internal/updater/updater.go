@@ -0,0 +1,268 @@
+// Package updater resolves an AppImage's embedded update-information
+// string and checks whether a newer build is available, the way
+// AppImageUpdate / appimagetool-produced AppImages do.
+package updater
+
+import (
+	"bufio"
+	"debug/elf"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+)
+
+// Info is a parsed update-information string, as embedded in an
+// AppImage's .upd_info ELF section.
+type Info struct {
+	Transport string // "zsync" or "gh-releases-zsync"
+
+	// Populated for Transport == "zsync".
+	ZsyncURL string
+
+	// Populated for Transport == "gh-releases-zsync".
+	Owner, Repo, Tag, Pattern string
+}
+
+// ParseInfo parses a raw update-information string of the form
+// "zsync|<url>" or "gh-releases-zsync|<user>|<repo>|<tag>|<pattern>".
+func ParseInfo(raw string) (Info, error) {
+	parts := strings.Split(strings.TrimSpace(raw), "|")
+	if len(parts) == 0 {
+		return Info{}, fmt.Errorf("empty update information")
+	}
+
+	switch parts[0] {
+	case "zsync":
+		if len(parts) != 2 {
+			return Info{}, fmt.Errorf("malformed zsync update information: %q", raw)
+		}
+		return Info{Transport: "zsync", ZsyncURL: parts[1]}, nil
+
+	case "gh-releases-zsync":
+		if len(parts) != 5 {
+			return Info{}, fmt.Errorf("malformed gh-releases-zsync update information: %q", raw)
+		}
+		return Info{
+			Transport: "gh-releases-zsync",
+			Owner:     parts[1],
+			Repo:      parts[2],
+			Tag:       parts[3],
+			Pattern:   parts[4],
+		}, nil
+
+	default:
+		return Info{}, fmt.Errorf("unsupported update transport: %q", parts[0])
+	}
+}
+
+// ReadEmbeddedInfo reads the update-information string embedded in an
+// AppImage's .upd_info ELF section, falling back to asking the AppImage
+// itself via --appimage-updateinfo when the section can't be read
+// directly (e.g. type 1, ISO9660-based AppImages).
+func ReadEmbeddedInfo(appImagePath string) (string, error) {
+	if raw, err := readUpdInfoSection(appImagePath); err == nil {
+		return raw, nil
+	}
+	return readUpdInfoViaCLI(appImagePath)
+}
+
+func readUpdInfoSection(appImagePath string) (string, error) {
+	f, err := elf.Open(appImagePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	section := f.Section(".upd_info")
+	if section == nil {
+		return "", fmt.Errorf("no .upd_info section in %s", appImagePath)
+	}
+
+	data, err := section.Data()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(data), "\x00"), nil
+}
+
+func readUpdInfoViaCLI(appImagePath string) (string, error) {
+	out, err := exec.Command(appImagePath, "--appimage-updateinfo").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read update information: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Result is the outcome of checking one app for updates.
+type Result struct {
+	Outdated    bool
+	LatestName  string
+	DownloadURL string
+}
+
+// Backend resolves the latest available version for one update transport.
+type Backend interface {
+	Check(localPath string, info Info) (*Result, error)
+}
+
+// BackendFor returns the Backend that handles info's transport.
+func BackendFor(info Info) (Backend, error) {
+	switch info.Transport {
+	case "zsync":
+		return ZsyncBackend{}, nil
+	case "gh-releases-zsync":
+		return GHReleasesBackend{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported update transport: %q", info.Transport)
+	}
+}
+
+func httpClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// ZsyncBackend resolves updates published as a zsync control file
+// alongside the AppImage itself.
+type ZsyncBackend struct{}
+
+func (ZsyncBackend) Check(localPath string, info Info) (*Result, error) {
+	resp, err := httpClient().Get(info.ZsyncURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch zsync file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch zsync file: %s", resp.Status)
+	}
+
+	header, err := parseZsyncHeader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse zsync header: %w", err)
+	}
+
+	localStat, err := os.Stat(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat local AppImage: %w", err)
+	}
+
+	outdated := !header.MTime.IsZero() && header.MTime.After(localStat.ModTime())
+
+	return &Result{
+		Outdated:    outdated,
+		LatestName:  header.Filename,
+		DownloadURL: resolveSibling(info.ZsyncURL, header.Filename),
+	}, nil
+}
+
+type zsyncHeader struct {
+	Filename string
+	MTime    time.Time
+}
+
+// parseZsyncHeader reads the plain-text header lines at the start of a
+// .zsync control file, stopping at the first blank line.
+func parseZsyncHeader(r io.Reader) (zsyncHeader, error) {
+	var header zsyncHeader
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "Filename":
+			header.Filename = value
+		case "MTime":
+			if t, err := time.Parse(time.RFC1123, value); err == nil {
+				header.MTime = t
+			}
+		}
+	}
+
+	return header, scanner.Err()
+}
+
+// resolveSibling resolves filename relative to rawURL's directory, the way
+// a zsync client finds the file a .zsync control file describes.
+func resolveSibling(rawURL, filename string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	u.Path = path.Join(path.Dir(u.Path), filename)
+	return u.String()
+}
+
+// GHReleasesBackend resolves updates via the GitHub releases API, the
+// transport used by gh-releases-zsync update information.
+type GHReleasesBackend struct{}
+
+type ghRelease struct {
+	TagName string    `json:"tag_name"`
+	Assets  []ghAsset `json:"assets"`
+}
+
+type ghAsset struct {
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+func (GHReleasesBackend) Check(localPath string, info Info) (*Result, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", info.Owner, info.Repo)
+
+	resp, err := httpClient().Get(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query GitHub releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query GitHub releases: %s", resp.Status)
+	}
+
+	var releases []ghRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub releases: %w", err)
+	}
+
+	for _, release := range releases {
+		if info.Tag != "" && info.Tag != "latest" && release.TagName != info.Tag {
+			continue
+		}
+
+		for _, asset := range release.Assets {
+			matched, err := path.Match(info.Pattern, asset.Name)
+			if err != nil || !matched {
+				continue
+			}
+
+			localStat, err := os.Stat(localPath)
+			outdated := err != nil || localStat.Size() != asset.Size
+
+			return &Result{
+				Outdated:    outdated,
+				LatestName:  asset.Name,
+				DownloadURL: asset.BrowserDownloadURL,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no release asset matching %q found", info.Pattern)
+}
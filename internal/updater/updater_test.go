@@ -0,0 +1,65 @@
+package updater
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseInfo(t *testing.T) {
+	info, err := ParseInfo("zsync|https://example.com/App.AppImage.zsync")
+	if err != nil {
+		t.Fatalf("ParseInfo(zsync) failed: %v", err)
+	}
+	if info.Transport != "zsync" || info.ZsyncURL != "https://example.com/App.AppImage.zsync" {
+		t.Errorf("ParseInfo(zsync) = %+v", info)
+	}
+
+	info, err = ParseInfo("gh-releases-zsync|foo|bar|latest|*-x86_64.AppImage")
+	if err != nil {
+		t.Fatalf("ParseInfo(gh-releases-zsync) failed: %v", err)
+	}
+	if info.Owner != "foo" || info.Repo != "bar" || info.Tag != "latest" || info.Pattern != "*-x86_64.AppImage" {
+		t.Errorf("ParseInfo(gh-releases-zsync) = %+v", info)
+	}
+
+	if _, err := ParseInfo("bogus|1|2"); err == nil {
+		t.Error("expected error for unsupported transport")
+	}
+	if _, err := ParseInfo("zsync|only-one-part-missing"); err != nil {
+		t.Error("zsync with a URL containing no extra pipes should parse fine")
+	}
+	if _, err := ParseInfo("zsync"); err == nil {
+		t.Error("expected error for zsync with no URL")
+	}
+}
+
+func TestParseZsyncHeader(t *testing.T) {
+	raw := "zsync: 0.6.2\n" +
+		"Filename: App-x86_64.AppImage\n" +
+		"MTime: Fri, 15 Mar 2024 10:00:00 +0000\n" +
+		"Blocksize: 4096\n" +
+		"Length: 123456\n" +
+		"\n" +
+		"binary control data follows"
+
+	header, err := parseZsyncHeader(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parseZsyncHeader failed: %v", err)
+	}
+	if header.Filename != "App-x86_64.AppImage" {
+		t.Errorf("Filename = %q", header.Filename)
+	}
+	want := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	if !header.MTime.Equal(want) {
+		t.Errorf("MTime = %v, want %v", header.MTime, want)
+	}
+}
+
+func TestResolveSibling(t *testing.T) {
+	got := resolveSibling("https://example.com/releases/latest/App.AppImage.zsync", "App-v2.AppImage")
+	want := "https://example.com/releases/latest/App-v2.AppImage"
+	if got != want {
+		t.Errorf("resolveSibling = %q, want %q", got, want)
+	}
+}
@@ -3,12 +3,14 @@ package main
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/sivepanda/teabag/internal/manifest"
 )
 
 const (
@@ -19,6 +21,8 @@ type step int
 
 const (
 	stepFileBrowser step = iota
+	stepExtract
+	stepScope
 	stepAppImageDir
 	stepAppName
 	stepDescription
@@ -30,13 +34,18 @@ const (
 )
 
 type installCompleteMsg struct {
-	err error
+	err     error
+	id      string
+	result  InstallResult
+	message string
 }
 
 type fileEntry struct {
-	name  string
-	path  string
-	isDir bool
+	name    string
+	path    string
+	isDir   bool
+	size    int64
+	modTime time.Time
 }
 
 var (
@@ -47,34 +56,64 @@ var (
 )
 
 type model struct {
-	currentStep     step
-	appImagePath    string
-	appImageDir     string
-	appName         string
-	description     string
-	iconPath        string
-	categories      string
-	input           string
-	error           string
-	message         string
-	configPath      string
-	firstTimeSetup  bool
-	desktopFilePath string
+	currentStep    step
+	appImagePath   string
+	appImageDir    string
+	appName        string
+	description    string
+	iconPath       string
+	categories     string
+	input          string
+	error          string
+	message        string
+	configPath     string
+	manifestPath   string
+	firstTimeSetup bool
+	assignedID     string
+
+	// Install scope fields
+	scope         string // "user" or "system"
+	scopeForced   bool   // set by --user/--system, skips the stepScope picker
+	dryRun        bool
+	backend       DesktopBackend
+	installResult InstallResult
+
+	// Extraction fields
+	extracting bool
+	extractErr string
 
 	// File browser fields
 	currentDir string
 	files      []fileEntry
 	cursor     int
+
+	// Recursive search fields
+	searchMode      bool
+	searching       bool
+	searchResults   []fileEntry
+	searchSort      sortMode
+	searchResultsCh chan fileEntry
+	searchCancelCh  chan struct{}
 }
 
-func initialModel(appImagePath string) model {
+// initialModel builds the starting model for an install run. forceScope,
+// set from the --user/--system CLI flags, overrides the persisted SCOPE
+// and skips the first-time stepScope picker; pass "" to respect it.
+func initialModel(appImagePath, forceScope string, dryRun bool) model {
 	homeDir, _ := os.UserHomeDir()
 	configPath := filepath.Join(homeDir, configFile)
 
 	m := model{
 		appImagePath: appImagePath,
 		configPath:   configPath,
+		manifestPath: manifest.PathFor(configPath),
 		categories:   "Utility;",
+		scope:        "user",
+		dryRun:       dryRun,
+	}
+	if forceScope != "" {
+		m.scope = forceScope
+		m.scopeForced = true
 	}
 
 	// If no app image path provided, start with file browser
@@ -88,48 +127,63 @@ func initialModel(appImagePath string) model {
 	// Check if config exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		m.firstTimeSetup = true
-		m.currentStep = stepAppImageDir
-		m.input = filepath.Join(homeDir, "Applications")
-	} else {
-		// Load existing config
-		if dir, err := loadConfig(configPath); err == nil {
-			m.appImageDir = dir
-			m.currentStep = stepAppName
+		if m.scopeForced {
+			m.currentStep = stepAppImageDir
+			m.input = filepath.Join(homeDir, "Applications")
 		} else {
-			m.currentStep = stepError
-			m.error = fmt.Sprintf("Failed to load config: %v", err)
+			m.currentStep = stepScope
+		}
+	} else if cfg, err := loadConfig(configPath); err == nil {
+		m.appImageDir = cfg.AppImageDir
+		if !m.scopeForced {
+			m.scope = cfg.Scope
 		}
+		m.currentStep = stepAppName
+	} else {
+		m.currentStep = stepError
+		m.error = fmt.Sprintf("Failed to load config: %v", err)
 	}
 
 	return m
 }
 
-func loadConfig(path string) (string, error) {
+// config is teabag's persisted appimage-installer.conf, a flat shell-style
+// KEY="value" file.
+type config struct {
+	AppImageDir string
+	Scope       string // "user" or "system"
+}
+
+func loadConfig(path string) (config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return "", err
+		return config{}, err
 	}
 
-	// Parse APPIMAGE_DIR="path"
+	cfg := config{Scope: "user"}
 	lines := strings.Split(string(data), "\n")
 	for _, line := range lines {
-		if strings.HasPrefix(line, "APPIMAGE_DIR=") {
-			dir := strings.TrimPrefix(line, "APPIMAGE_DIR=")
-			dir = strings.Trim(dir, "\"")
-			return dir, nil
+		switch {
+		case strings.HasPrefix(line, "APPIMAGE_DIR="):
+			cfg.AppImageDir = strings.Trim(strings.TrimPrefix(line, "APPIMAGE_DIR="), "\"")
+		case strings.HasPrefix(line, "SCOPE="):
+			cfg.Scope = strings.Trim(strings.TrimPrefix(line, "SCOPE="), "\"")
 		}
 	}
 
-	return "", fmt.Errorf("APPIMAGE_DIR not found in config")
+	if cfg.AppImageDir == "" {
+		return config{}, fmt.Errorf("APPIMAGE_DIR not found in config")
+	}
+	return cfg, nil
 }
 
-func saveConfig(path, appImageDir string) error {
+func saveConfig(path, appImageDir, scope string) error {
 	dirPath := filepath.Dir(path)
 	if err := os.MkdirAll(dirPath, 0755); err != nil {
 		return err
 	}
 
-	content := fmt.Sprintf("APPIMAGE_DIR=\"%s\"\n", appImageDir)
+	content := fmt.Sprintf("APPIMAGE_DIR=\"%s\"\nSCOPE=\"%s\"\n", appImageDir, scope)
 	return os.WriteFile(path, []byte(content), 0644)
 }
 
@@ -180,27 +234,111 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "ctrl+c", "esc":
+		case "ctrl+c":
+			return m, tea.Quit
+
+		case "esc":
+			if m.currentStep == stepFileBrowser && m.searchMode {
+				m.exitSearchMode()
+				return m, nil
+			}
 			return m, tea.Quit
 
-		case "up", "k":
-			if m.currentStep == stepFileBrowser && m.cursor > 0 {
+		case "up":
+			switch {
+			case m.currentStep == stepFileBrowser && m.cursor > 0:
+				m.cursor--
+			case m.currentStep == stepScope && m.cursor > 0:
 				m.cursor--
 			}
 
-		case "down", "j":
-			if m.currentStep == stepFileBrowser && m.cursor < len(m.files)-1 {
+		case "down":
+			switch {
+			case m.currentStep == stepFileBrowser && m.cursor < m.visibleFileCount()-1:
+				m.cursor++
+			case m.currentStep == stepScope && m.cursor < 1:
 				m.cursor++
 			}
 
+		case "k":
+			switch {
+			case m.currentStep == stepFileBrowser && m.searchMode:
+				m.input += "k"
+				m.cursor = 0
+			case m.currentStep == stepFileBrowser:
+				if m.cursor > 0 {
+					m.cursor--
+				}
+			case m.acceptsFreeText():
+				m.input += "k"
+			}
+
+		case "j":
+			switch {
+			case m.currentStep == stepFileBrowser && m.searchMode:
+				m.input += "j"
+				m.cursor = 0
+			case m.currentStep == stepFileBrowser:
+				if m.cursor < m.visibleFileCount()-1 {
+					m.cursor++
+				}
+			case m.acceptsFreeText():
+				m.input += "j"
+			}
+
+		case "/":
+			switch {
+			case m.currentStep == stepFileBrowser && m.searchMode:
+				m.exitSearchMode()
+			case m.currentStep == stepFileBrowser:
+				return m, m.startSearch()
+			case m.acceptsFreeText():
+				m.input += "/"
+			}
+
+		case "r":
+			switch {
+			// r and s are common letters in real app names ("Cursor",
+			// "VSCodium"), so only treat them as mode keys while the
+			// filter is empty; once the user has started typing a
+			// filter, every key goes into it like any other letter.
+			case m.currentStep == stepFileBrowser && m.searchMode && m.input == "":
+				m.exitSearchMode()
+			case m.currentStep == stepFileBrowser && m.searchMode:
+				m.input += "r"
+				m.cursor = 0
+			case m.currentStep == stepFileBrowser:
+				return m, m.startSearch()
+			case m.acceptsFreeText():
+				m.input += "r"
+			}
+
+		case "s":
+			switch {
+			case m.currentStep == stepFileBrowser && m.searchMode && m.input == "":
+				m.searchSort = (m.searchSort + 1) % 3
+			case m.currentStep == stepFileBrowser && m.searchMode:
+				m.input += "s"
+				m.cursor = 0
+			case m.currentStep == stepFileBrowser:
+				// no-op: sort toggling only applies while searching
+			case m.acceptsFreeText():
+				m.input += "s"
+			}
+
 		case "enter":
 			newModel, cmd := m.handleEnter()
 			return newModel, cmd
 
 		case "backspace":
 			if m.currentStep == stepFileBrowser {
-				// Go to parent directory
-				if m.currentDir != "/" {
+				if m.searchMode {
+					if len(m.input) > 0 {
+						m.input = m.input[:len(m.input)-1]
+						m.cursor = 0
+					}
+				} else if m.currentDir != "/" {
+					// Go to parent directory
 					m.currentDir = filepath.Dir(m.currentDir)
 					m.loadDirectory()
 				}
@@ -209,17 +347,51 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		default:
-			if m.currentStep != stepProcessing && m.currentStep != stepComplete && m.currentStep != stepError && m.currentStep != stepFileBrowser {
+			if m.currentStep == stepFileBrowser && m.searchMode {
+				m.input += msg.String()
+				m.cursor = 0
+				return m, nil
+			}
+			if m.acceptsFreeText() {
 				m.input += msg.String()
 			}
 		}
 
+	case appImageFoundMsg:
+		if !m.searching {
+			// A stray result from a search the user has already left.
+			return m, nil
+		}
+		m.searchResults = append(m.searchResults, msg.entry)
+		return m, waitForSearchEvent(m.searchResultsCh)
+
+	case searchDoneMsg:
+		m.searching = false
+		return m, nil
+
+	case extractedInfoMsg:
+		m.extracting = false
+		if msg.err != nil {
+			m.extractErr = msg.err.Error()
+		} else {
+			m.appName = msg.info.appName
+			m.description = msg.info.description
+			if msg.info.categories != "" {
+				m.categories = msg.info.categories
+			}
+			m.iconPath = msg.info.iconPath
+		}
+		return m, nil
+
 	case installCompleteMsg:
 		if msg.err != nil {
 			m.currentStep = stepError
 			m.error = msg.err.Error()
 		} else {
 			m.currentStep = stepComplete
+			m.assignedID = msg.id
+			m.installResult = msg.result
+			m.message = msg.message
 		}
 		return m, nil
 	}
@@ -227,39 +399,84 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// acceptsFreeText reports whether the current step treats ordinary
+// keystrokes as text entry into m.input.
+func (m model) acceptsFreeText() bool {
+	switch m.currentStep {
+	case stepProcessing, stepComplete, stepError, stepFileBrowser, stepExtract, stepScope:
+		return false
+	default:
+		return true
+	}
+}
+
 func (m model) handleEnter() (model, tea.Cmd) {
 	switch m.currentStep {
 	case stepFileBrowser:
-		if len(m.files) == 0 {
+		files := m.visibleFiles()
+		if len(files) == 0 || m.cursor >= len(files) {
 			return m, nil
 		}
 
-		selected := m.files[m.cursor]
+		selected := files[m.cursor]
 		if selected.isDir {
-			// Navigate into directory
+			// Navigate into directory (search mode has no directories to
+			// descend into; it's a flat list of matches).
 			m.currentDir = selected.path
 			m.loadDirectory()
 		} else {
-			// AppImage file selected
+			// AppImage file selected; look inside it for a bundled
+			// .desktop entry and icon before asking the user anything.
+			if m.searchMode {
+				m.exitSearchMode()
+			}
 			m.appImagePath = selected.path
+			m.currentStep = stepExtract
+			m.extracting = true
+			m.extractErr = ""
+			return m, extractAppImageMetadata(selected.path)
+		}
 
-			// Load config and proceed
-			homeDir, _ := os.UserHomeDir()
-			if _, err := os.Stat(m.configPath); os.IsNotExist(err) {
-				m.firstTimeSetup = true
+	case stepExtract:
+		if m.extracting {
+			return m, nil
+		}
+
+		// Load config and proceed
+		homeDir, _ := os.UserHomeDir()
+		if _, err := os.Stat(m.configPath); os.IsNotExist(err) {
+			m.firstTimeSetup = true
+			if m.scopeForced {
 				m.currentStep = stepAppImageDir
 				m.input = filepath.Join(homeDir, "Applications")
 			} else {
-				if dir, err := loadConfig(m.configPath); err == nil {
-					m.appImageDir = dir
-					m.currentStep = stepAppName
-				} else {
-					m.currentStep = stepError
-					m.error = fmt.Sprintf("Failed to load config: %v", err)
+				m.currentStep = stepScope
+				m.cursor = 0
+			}
+		} else {
+			if cfg, err := loadConfig(m.configPath); err == nil {
+				m.appImageDir = cfg.AppImageDir
+				if !m.scopeForced {
+					m.scope = cfg.Scope
 				}
+				m.currentStep = stepAppName
+				m.input = m.appName
+			} else {
+				m.currentStep = stepError
+				m.error = fmt.Sprintf("Failed to load config: %v", err)
 			}
 		}
 
+	case stepScope:
+		if m.cursor == 1 {
+			m.scope = "system"
+		} else {
+			m.scope = "user"
+		}
+		homeDir, _ := os.UserHomeDir()
+		m.currentStep = stepAppImageDir
+		m.input = filepath.Join(homeDir, "Applications")
+
 	case stepAppImageDir:
 		if m.input == "" {
 			homeDir, _ := os.UserHomeDir()
@@ -275,14 +492,14 @@ func (m model) handleEnter() (model, tea.Cmd) {
 		}
 
 		// Save config
-		if err := saveConfig(m.configPath, m.appImageDir); err != nil {
+		if err := saveConfig(m.configPath, m.appImageDir, m.scope); err != nil {
 			m.currentStep = stepError
 			m.error = fmt.Sprintf("Failed to save config: %v", err)
 			return m, nil
 		}
 
 		m.currentStep = stepAppName
-		m.input = ""
+		m.input = m.appName
 
 	case stepAppName:
 		if m.input == "" {
@@ -291,13 +508,13 @@ func (m model) handleEnter() (model, tea.Cmd) {
 		}
 		m.appName = m.input
 		m.currentStep = stepDescription
-		m.input = ""
+		m.input = m.description
 		m.error = ""
 
 	case stepDescription:
 		m.description = m.input
 		m.currentStep = stepIcon
-		m.input = ""
+		m.input = m.iconPath
 
 	case stepIcon:
 		if m.input != "" {
@@ -308,7 +525,7 @@ func (m model) handleEnter() (model, tea.Cmd) {
 		}
 		m.iconPath = m.input
 		m.currentStep = stepCategories
-		m.input = "Utility;"
+		m.input = m.categories
 		m.error = ""
 
 	case stepCategories:
@@ -345,85 +562,123 @@ func (m *model) install() tea.Cmd {
 	appImageFilename := filepath.Base(absPath)
 	destFile := filepath.Join(m.appImageDir, appImageFilename)
 
-	// Move AppImage to central location
-	if absPath != destFile {
-		if err := os.Rename(absPath, destFile); err != nil {
+	if m.backend == nil {
+		if m.dryRun {
+			m.backend = DryRunBackend{Scope: m.scope}
+		} else {
+			m.backend = backendForScope(m.scope)
+		}
+	}
+	backend := m.backend
+
+	if !m.dryRun {
+		// Move AppImage to central location
+		if absPath != destFile {
+			if err := os.Rename(absPath, destFile); err != nil {
+				return func() tea.Msg {
+					return installCompleteMsg{err: fmt.Errorf("failed to move file: %w", err)}
+				}
+			}
+		}
+
+		// Make executable
+		if err := os.Chmod(destFile, 0755); err != nil {
 			return func() tea.Msg {
-				return installCompleteMsg{err: fmt.Errorf("failed to move file: %w", err)}
+				return installCompleteMsg{err: fmt.Errorf("failed to make executable: %w", err)}
 			}
 		}
 	}
 
-	// Make executable
-	if err := os.Chmod(destFile, 0755); err != nil {
+	dryRun := m.dryRun
+	appName := m.appName
+
+	if dryRun {
+		// A dry run records nothing, so there's no manifest lock to take;
+		// just preview the id a real install would reserve.
+		appID := m.previewID()
+		params := InstallParams{
+			AppID:       appID,
+			AppName:     m.appName,
+			Description: m.description,
+			Categories:  m.categories,
+			Exec:        destFile,
+			IconPath:    m.iconPath,
+		}
 		return func() tea.Msg {
-			return installCompleteMsg{err: fmt.Errorf("failed to make executable: %w", err)}
+			result, err := backend.Install(params)
+			if err != nil {
+				return installCompleteMsg{err: fmt.Errorf("failed to install desktop entry: %w", err)}
+			}
+			return installCompleteMsg{id: appID, result: result, message: "Dry run complete; nothing was installed or recorded."}
 		}
 	}
 
-	// Build desktop entry content
-	desktopEntry := fmt.Sprintf("[Desktop Entry]\nName=%s\nExec=%s\nType=Application\nCategories=%s\n",
-		m.appName, destFile, m.categories)
+	return func() tea.Msg {
+		appID, result, err := m.installAndRecord(backend, destFile)
+		if err != nil {
+			return installCompleteMsg{err: err}
+		}
 
-	if m.description != "" {
-		desktopEntry += fmt.Sprintf("Comment=%s\n", m.description)
+		message := fmt.Sprintf("Installation complete! %s should now appear in your application launcher", appName)
+		return installCompleteMsg{id: appID, result: result, message: message}
 	}
+}
 
-	if m.iconPath != "" {
-		desktopEntry += fmt.Sprintf("Icon=%s\n", m.iconPath)
+// previewID picks the id a real install would reserve, for display in a
+// dry run. It deliberately doesn't lock the manifest: a dry run records
+// nothing, so there's nothing for it to race with.
+func (m *model) previewID() string {
+	mf, err := manifest.Load(m.manifestPath)
+	if err != nil {
+		return slugify(m.appName)
 	}
+	return mf.UniqueID(slugify(m.appName))
+}
 
-	// Create desktop entry in temp location first
-	desktopFilename := strings.ToLower(strings.ReplaceAll(m.appName, " ", "-")) + ".desktop"
-	tmpDesktopFile := filepath.Join(os.TempDir(), desktopFilename)
-
-	if err := os.WriteFile(tmpDesktopFile, []byte(desktopEntry), 0644); err != nil {
-		return func() tea.Msg {
-			return installCompleteMsg{err: fmt.Errorf("failed to create temp desktop entry: %w", err)}
+// installAndRecord reserves the manifest id, installs the desktop entry via
+// backend, and records the resulting app, all under a single manifest
+// lock, so that two concurrent installs can't reserve the same id and
+// overwrite each other's entry.
+func (m *model) installAndRecord(backend DesktopBackend, destFile string) (id string, result InstallResult, err error) {
+	lockErr := manifest.WithLock(m.manifestPath, func(mf *manifest.Manifest) error {
+		id = mf.UniqueID(slugify(m.appName))
+
+		var installErr error
+		result, installErr = backend.Install(InstallParams{
+			AppID:       id,
+			AppName:     m.appName,
+			Description: m.description,
+			Categories:  m.categories,
+			Exec:        destFile,
+			IconPath:    m.iconPath,
+		})
+		if installErr != nil {
+			return fmt.Errorf("failed to install desktop entry: %w", installErr)
 		}
-	}
-
-	desktopDir := "/usr/share/applications"
-	m.desktopFilePath = filepath.Join(desktopDir, desktopFilename)
 
-	// Check if pkexec is available
-	if _, err := exec.LookPath("pkexec"); err == nil {
-		// Use pkexec (shows graphical prompt, doesn't interrupt TUI)
-		return func() tea.Msg {
-			// Copy desktop file
-			cmd := exec.Command("pkexec", "cp", tmpDesktopFile, m.desktopFilePath)
-			if err := cmd.Run(); err != nil {
-				return installCompleteMsg{err: fmt.Errorf("failed to copy desktop file: %w", err)}
-			}
-
-			// Update desktop database
-			if _, err := exec.LookPath("update-desktop-database"); err == nil {
-				cmd := exec.Command("pkexec", "update-desktop-database", desktopDir)
-				cmd.Run() // Ignore errors
-			}
-
-			m.message = fmt.Sprintf("Installation complete! %s should now appear in your application launcher", m.appName)
-			return installCompleteMsg{err: nil}
-		}
+		mf.Add(manifest.App{
+			ID:              id,
+			Name:            m.appName,
+			Categories:      m.categories,
+			AppImagePath:    destFile,
+			DesktopFilePath: result.DesktopFilePath,
+			IconPath:        result.IconPath,
+			Scope:           m.scope,
+			InstalledAt:     time.Now(),
+		})
+		return nil
+	})
+	if lockErr != nil {
+		return "", InstallResult{}, lockErr
 	}
+	return id, result, nil
+}
 
-	// Fall back to sudo with tea.ExecProcess (suspends TUI temporarily)
-	return tea.Sequence(
-		tea.ExecProcess(exec.Command("sudo", "cp", tmpDesktopFile, m.desktopFilePath), func(err error) tea.Msg {
-			if err != nil {
-				return installCompleteMsg{err: fmt.Errorf("failed to copy desktop file: %w", err)}
-			}
-
-			// Update desktop database
-			if _, err := exec.LookPath("update-desktop-database"); err == nil {
-				cmd := exec.Command("sudo", "update-desktop-database", desktopDir)
-				cmd.Run() // Ignore errors
-			}
-
-			m.message = fmt.Sprintf("Installation complete! %s should now appear in your application launcher", m.appName)
-			return installCompleteMsg{err: nil}
-		}),
-	)
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
 }
 
 func (m model) View() string {
@@ -433,6 +688,36 @@ func (m model) View() string {
 
 	switch m.currentStep {
 	case stepFileBrowser:
+		if m.searchMode {
+			status := "Searching"
+			if !m.searching {
+				status = "Search complete"
+			}
+			s.WriteString(infoStyle.Render(fmt.Sprintf("%s under %s (sort: %s)", status, m.currentDir, m.searchSort)) + "\n")
+			s.WriteString(fmt.Sprintf("Filter: %s\n\n", m.input))
+
+			files := m.visibleFiles()
+			if len(files) == 0 {
+				s.WriteString(infoStyle.Render("No matching AppImages found yet") + "\n")
+			} else {
+				for i, file := range files {
+					cursor := " "
+					if i == m.cursor {
+						cursor = ">"
+					}
+
+					line := fmt.Sprintf("%s 📄 %-40s %10s  %s", cursor, file.name, formatSize(file.size), formatModTime(file.modTime))
+					if i == m.cursor {
+						line = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Bold(true).Render(line)
+					}
+					s.WriteString(line + "\n")
+				}
+			}
+
+			s.WriteString("\n(type to filter, ↑/↓: navigate, s: sort, Enter: select, Esc: back to browsing)")
+			break
+		}
+
 		s.WriteString(fmt.Sprintf("Current directory: %s\n\n", m.currentDir))
 
 		if len(m.files) == 0 {
@@ -457,7 +742,47 @@ func (m model) View() string {
 			}
 		}
 
-		s.WriteString("\n(↑/↓ or j/k: navigate, Enter: select, Backspace: parent dir, Ctrl+C: quit)")
+		s.WriteString("\n(↑/↓ or j/k: navigate, /: search recursively, Enter: select, Backspace: parent dir, Ctrl+C: quit)")
+
+	case stepExtract:
+		s.WriteString(fmt.Sprintf("Installing: %s\n\n", filepath.Base(m.appImagePath)))
+
+		if m.extracting {
+			s.WriteString(infoStyle.Render("➜ Looking for a bundled .desktop file and icon...") + "\n")
+			break
+		}
+
+		if m.extractErr != "" {
+			s.WriteString(infoStyle.Render("No bundled metadata found; you'll fill it in by hand.") + "\n")
+		} else {
+			s.WriteString(infoStyle.Render("Detected from the AppImage (press Enter to accept, or edit it on the next screens):") + "\n\n")
+			s.WriteString(fmt.Sprintf("Name:       %s\n", orNone(m.appName)))
+			s.WriteString(fmt.Sprintf("Comment:    %s\n", orNone(m.description)))
+			s.WriteString(fmt.Sprintf("Categories: %s\n", orNone(m.categories)))
+			s.WriteString(fmt.Sprintf("Icon:       %s\n", orNone(m.iconPath)))
+		}
+		s.WriteString("\n(Press Enter to continue, Ctrl+C to quit)")
+
+	case stepScope:
+		s.WriteString(infoStyle.Render("First-time setup: Choose install scope") + "\n\n")
+
+		options := []struct{ label, desc string }{
+			{"user", "Install for your user only, under $XDG_DATA_HOME (no root required) — recommended"},
+			{"system", "Install system-wide under /usr/share (requires root via pkexec)"},
+		}
+		for i, opt := range options {
+			cursor := " "
+			if i == m.cursor {
+				cursor = ">"
+			}
+
+			line := fmt.Sprintf("%s %s - %s", cursor, opt.label, opt.desc)
+			if i == m.cursor {
+				line = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Bold(true).Render(line)
+			}
+			s.WriteString(line + "\n")
+		}
+		s.WriteString("\n(↑/↓: choose, Enter: confirm, Ctrl+C: quit)")
 
 	case stepAppImageDir:
 		s.WriteString(infoStyle.Render("First-time setup: Configure AppImage storage location") + "\n\n")
@@ -496,9 +821,13 @@ func (m model) View() string {
 
 	case stepComplete:
 		s.WriteString(successStyle.Render("✓ Installation complete!") + "\n\n")
+		s.WriteString(fmt.Sprintf("App id: %s\n", m.assignedID))
 		s.WriteString(fmt.Sprintf("AppImage: %s\n", filepath.Join(m.appImageDir, filepath.Base(m.appImagePath))))
-		s.WriteString(fmt.Sprintf("Desktop entry: %s\n\n", m.desktopFilePath))
-		s.WriteString(m.message + "\n\n")
+		s.WriteString(fmt.Sprintf("Desktop entry: %s\n", m.installResult.DesktopFilePath))
+		if m.installResult.IconPath != "" {
+			s.WriteString(fmt.Sprintf("Icon: %s\n", m.installResult.IconPath))
+		}
+		s.WriteString("\n" + m.message + "\n\n")
 		s.WriteString("(Press any key to exit)")
 
 	case stepError:
@@ -510,11 +839,32 @@ func (m model) View() string {
 }
 
 func main() {
-	var appImagePath string
-
 	if len(os.Args) >= 2 {
-		appImagePath = os.Args[1]
+		if handled, err := runSubcommand(os.Args[1], os.Args[2:]); handled {
+			if err != nil {
+				fmt.Println(errorStyle.Render("✗ " + err.Error()))
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	var appImagePath, forceScope string
+	var dryRun bool
+	for _, arg := range os.Args[1:] {
+		switch arg {
+		case "--user":
+			forceScope = "user"
+		case "--system":
+			forceScope = "system"
+		case "--dry-run":
+			dryRun = true
+		default:
+			appImagePath = arg
+		}
+	}
 
+	if appImagePath != "" {
 		// Check if file exists
 		if _, err := os.Stat(appImagePath); os.IsNotExist(err) {
 			fmt.Println(errorStyle.Render(fmt.Sprintf("✗ File not found: %s", appImagePath)))
@@ -522,7 +872,7 @@ func main() {
 		}
 	}
 
-	p := tea.NewProgram(initialModel(appImagePath))
+	p := tea.NewProgram(initialModel(appImagePath, forceScope, dryRun))
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
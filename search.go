@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sortMode controls how recursive search results are ordered.
+type sortMode int
+
+const (
+	sortMtime sortMode = iota
+	sortName
+	sortSize
+)
+
+func (s sortMode) String() string {
+	switch s {
+	case sortName:
+		return "name"
+	case sortSize:
+		return "size"
+	default:
+		return "newest first"
+	}
+}
+
+// appImageFoundMsg carries a single AppImage discovered by a running
+// recursive search.
+type appImageFoundMsg struct {
+	entry fileEntry
+}
+
+// searchDoneMsg signals that a recursive search has finished, either by
+// walking the whole tree or by being cancelled.
+type searchDoneMsg struct{}
+
+// skipDirNames are directory names a recursive search never descends
+// into because they're noise (VCS metadata, dependency trees). Hidden
+// directories, /proc and /sys are skipped separately by walkForAppImages.
+var skipDirNames = map[string]bool{
+	"node_modules": true,
+	".git":         true,
+}
+
+// startSearch begins a recursive, cancellable walk of m.currentDir for
+// AppImages and returns the tea.Cmd that listens for the first result.
+func (m *model) startSearch() tea.Cmd {
+	results := make(chan fileEntry)
+	cancel := make(chan struct{})
+
+	m.searchMode = true
+	m.searching = true
+	m.searchResults = nil
+	m.searchSort = sortMtime
+	m.input = ""
+	m.cursor = 0
+	m.searchResultsCh = results
+	m.searchCancelCh = cancel
+
+	root := m.currentDir
+	go walkForAppImages(root, results, cancel)
+
+	return waitForSearchEvent(results)
+}
+
+// exitSearchMode cancels any in-flight walk and returns the file browser to
+// its normal, non-recursive listing.
+func (m *model) exitSearchMode() {
+	m.cancelSearch()
+	m.searchMode = false
+	m.searching = false
+	m.searchResults = nil
+	m.input = ""
+	m.cursor = 0
+}
+
+func (m *model) cancelSearch() {
+	if !m.searching || m.searchCancelCh == nil {
+		return
+	}
+	select {
+	case <-m.searchCancelCh:
+		// Already closed.
+	default:
+		close(m.searchCancelCh)
+	}
+}
+
+// visibleFiles returns whatever the file browser should currently display:
+// the filtered, sorted search results while searching, or the plain
+// directory listing otherwise.
+func (m model) visibleFiles() []fileEntry {
+	if !m.searchMode {
+		return m.files
+	}
+
+	filtered := make([]fileEntry, 0, len(m.searchResults))
+	for _, entry := range m.searchResults {
+		if fuzzyMatch(entry.name, m.input) {
+			filtered = append(filtered, entry)
+		}
+	}
+	sortFileEntries(filtered, m.searchSort)
+	return filtered
+}
+
+func (m model) visibleFileCount() int {
+	return len(m.visibleFiles())
+}
+
+// walkForAppImages walks root looking for AppImages, sending each one found
+// on results until the walk finishes or cancel is closed. It always closes
+// results when done.
+func walkForAppImages(root string, results chan<- fileEntry, cancel <-chan struct{}) {
+	defer close(results)
+
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		select {
+		case <-cancel:
+			return filepath.SkipAll
+		default:
+		}
+		if err != nil {
+			return nil
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || skipDirNames[name]) {
+				return filepath.SkipDir
+			}
+			if path == "/proc" || path == "/sys" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(strings.ToLower(d.Name()), ".appimage") {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		entry := fileEntry{
+			name:    d.Name(),
+			path:    path,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+
+		select {
+		case results <- entry:
+		case <-cancel:
+			return filepath.SkipAll
+		}
+		return nil
+	})
+}
+
+// waitForSearchEvent returns a tea.Cmd that blocks for the next result (or
+// the channel closing) from a running search. Update() re-invokes this
+// after every appImageFoundMsg so results stream in without blocking the
+// rest of the UI.
+func waitForSearchEvent(results chan fileEntry) tea.Cmd {
+	return func() tea.Msg {
+		entry, ok := <-results
+		if !ok {
+			return searchDoneMsg{}
+		}
+		return appImageFoundMsg{entry: entry}
+	}
+}
+
+// fuzzyMatch reports whether query's characters all appear in name, in
+// order, case-insensitively -- the usual fuzzy-finder subsequence match.
+func fuzzyMatch(name, query string) bool {
+	if query == "" {
+		return true
+	}
+	name = strings.ToLower(name)
+	query = strings.ToLower(query)
+
+	qi := 0
+	for i := 0; i < len(name) && qi < len(query); i++ {
+		if name[i] == query[qi] {
+			qi++
+		}
+	}
+	return qi == len(query)
+}
+
+func sortFileEntries(entries []fileEntry, mode sortMode) {
+	sort.Slice(entries, func(i, j int) bool {
+		switch mode {
+		case sortName:
+			return strings.ToLower(entries[i].name) < strings.ToLower(entries[j].name)
+		case sortSize:
+			return entries[i].size > entries[j].size
+		default:
+			return entries[i].modTime.After(entries[j].modTime)
+		}
+	})
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func formatModTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	cases := []struct {
+		name, query string
+		want        bool
+	}{
+		{"Blender.AppImage", "blndr", true},
+		{"Blender.AppImage", "ble", true},
+		{"Blender.AppImage", "", true},
+		{"Blender.AppImage", "xyz", false},
+		{"Blender.AppImage", "render", false},
+	}
+	for _, c := range cases {
+		if got := fuzzyMatch(c.name, c.query); got != c.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", c.name, c.query, got, c.want)
+		}
+	}
+}
+
+func TestSortFileEntries(t *testing.T) {
+	now := time.Unix(1000, 0)
+	entries := []fileEntry{
+		{name: "b", size: 10, modTime: now.Add(-time.Hour)},
+		{name: "a", size: 30, modTime: now},
+		{name: "c", size: 20, modTime: now.Add(-2 * time.Hour)},
+	}
+
+	sortFileEntries(entries, sortName)
+	if entries[0].name != "a" || entries[1].name != "b" || entries[2].name != "c" {
+		t.Errorf("sortName order wrong: %+v", entries)
+	}
+
+	sortFileEntries(entries, sortSize)
+	if entries[0].size != 30 || entries[2].size != 10 {
+		t.Errorf("sortSize order wrong: %+v", entries)
+	}
+
+	sortFileEntries(entries, sortMtime)
+	if entries[0].name != "a" || entries[2].name != "c" {
+		t.Errorf("sortMtime order wrong: %+v", entries)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	cases := []struct {
+		size int64
+		want string
+	}{
+		{500, "500 B"},
+		{2048, "2.0 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+	}
+	for _, c := range cases {
+		if got := formatSize(c.size); got != c.want {
+			t.Errorf("formatSize(%d) = %q, want %q", c.size, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/sivepanda/teabag/internal/manifest"
+)
+
+// upgradeModel drives the `teabag upgrade` TUI: the update-check screen
+// that lists outdated apps and lets the user pick which ones to download.
+type upgradeModel struct {
+	loading  bool
+	err      string
+	statuses []appUpdateStatus
+	selected map[string]bool
+	cursor   int
+	message  string
+}
+
+func newUpgradeModel() upgradeModel {
+	return upgradeModel{loading: true, selected: map[string]bool{}}
+}
+
+type upgradeChecksMsg struct {
+	statuses []appUpdateStatus
+	err      error
+}
+
+type upgradeDownloadMsg struct {
+	id  string
+	err error
+}
+
+func (m upgradeModel) Init() tea.Cmd {
+	return loadUpgradeStatuses
+}
+
+func loadUpgradeStatuses() tea.Msg {
+	path, err := manifestPath()
+	if err != nil {
+		return upgradeChecksMsg{err: err}
+	}
+
+	mf, err := manifest.Load(path)
+	if err != nil {
+		return upgradeChecksMsg{err: err}
+	}
+
+	return upgradeChecksMsg{statuses: checkForUpdates(mf.Sorted())}
+}
+
+// outdated returns just the apps that have updates available, in the
+// order they'll be shown and navigated.
+func (m upgradeModel) outdated() []appUpdateStatus {
+	var result []appUpdateStatus
+	for _, status := range m.statuses {
+		if status.Err == nil && status.Result != nil && status.Result.Outdated {
+			result = append(result, status)
+		}
+	}
+	return result
+}
+
+func (m upgradeModel) downloadSelected() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, status := range m.outdated() {
+		if !m.selected[status.App.ID] {
+			continue
+		}
+		status := status
+		cmds = append(cmds, func() tea.Msg {
+			err := downloadReplacement(status.App, status.Result)
+			return upgradeDownloadMsg{id: status.App.ID, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m upgradeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case upgradeChecksMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err.Error()
+		} else {
+			m.statuses = msg.statuses
+		}
+		return m, nil
+
+	case upgradeDownloadMsg:
+		if msg.err != nil {
+			m.message = fmt.Sprintf("✗ %s: %v", msg.id, msg.err)
+		} else {
+			m.message = fmt.Sprintf("✓ Updated %s", msg.id)
+			delete(m.selected, msg.id)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return m, tea.Quit
+
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case "down", "j":
+			if m.cursor < len(m.outdated())-1 {
+				m.cursor++
+			}
+
+		case " ":
+			apps := m.outdated()
+			if m.cursor < len(apps) {
+				id := apps[m.cursor].App.ID
+				m.selected[id] = !m.selected[id]
+			}
+
+		case "enter":
+			return m, m.downloadSelected()
+		}
+	}
+
+	return m, nil
+}
+
+func (m upgradeModel) View() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("teabag upgrade") + "\n\n")
+
+	if m.loading {
+		s.WriteString(infoStyle.Render("➜ Checking for updates...") + "\n")
+		return s.String()
+	}
+
+	if m.err != "" {
+		s.WriteString(errorStyle.Render("✗ "+m.err) + "\n")
+		return s.String()
+	}
+
+	apps := m.outdated()
+	if len(apps) == 0 {
+		s.WriteString(successStyle.Render("✓ Everything is up to date") + "\n")
+		return s.String()
+	}
+
+	for i, status := range apps {
+		cursor := " "
+		if i == m.cursor {
+			cursor = ">"
+		}
+		check := " "
+		if m.selected[status.App.ID] {
+			check = "x"
+		}
+
+		line := fmt.Sprintf("%s [%s] %-20s %s -> %s", cursor, check, status.App.Name,
+			filepath.Base(status.App.AppImagePath), status.Result.LatestName)
+		if i == m.cursor {
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Bold(true).Render(line)
+		}
+		s.WriteString(line + "\n")
+	}
+
+	if m.message != "" {
+		s.WriteString("\n" + m.message + "\n")
+	}
+
+	s.WriteString("\n(↑/↓ or j/k: navigate, space: toggle, Enter: download selected, Ctrl+C: quit)")
+	return s.String()
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/sivepanda/teabag/internal/manifest"
+	"github.com/sivepanda/teabag/internal/updater"
+)
+
+// appUpdateStatus is the result of checking a single manifest entry for
+// updates.
+type appUpdateStatus struct {
+	App    manifest.App
+	Result *updater.Result
+	Err    error
+}
+
+// checkForUpdates resolves and checks each app's embedded update
+// information, one at a time. A failure on one app (missing update
+// information, an unreachable host) doesn't stop the rest from being
+// checked.
+func checkForUpdates(apps []manifest.App) []appUpdateStatus {
+	statuses := make([]appUpdateStatus, 0, len(apps))
+
+	for _, app := range apps {
+		status := appUpdateStatus{App: app}
+
+		raw, err := updater.ReadEmbeddedInfo(app.AppImagePath)
+		if err != nil {
+			status.Err = err
+			statuses = append(statuses, status)
+			continue
+		}
+
+		info, err := updater.ParseInfo(raw)
+		if err != nil {
+			status.Err = err
+			statuses = append(statuses, status)
+			continue
+		}
+
+		backend, err := updater.BackendFor(info)
+		if err != nil {
+			status.Err = err
+			statuses = append(statuses, status)
+			continue
+		}
+
+		status.Result, status.Err = backend.Check(app.AppImagePath, info)
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// downloadReplacement fetches result's asset and atomically swaps it in
+// for app's current AppImage, preserving the AppImage's path (and so the
+// desktop entry that points at it). The replacement is made executable
+// with mode 0755; the old file's mode is not copied.
+func downloadReplacement(app manifest.App, result *updater.Result) error {
+	if result == nil || result.DownloadURL == "" {
+		return fmt.Errorf("no download URL resolved for %s", app.ID)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Minute}
+	resp, err := client.Get(result.DownloadURL)
+	if err != nil {
+		return fmt.Errorf("failed to download update: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download update: %s", resp.Status)
+	}
+
+	newPath := app.AppImagePath + ".new"
+	out, err := os.Create(newPath)
+	if err != nil {
+		return fmt.Errorf("failed to create download file: %w", err)
+	}
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		os.Remove(newPath)
+		return fmt.Errorf("failed to save update: %w", err)
+	}
+	out.Close()
+
+	if err := os.Chmod(newPath, 0755); err != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("failed to make update executable: %w", err)
+	}
+
+	if err := os.Rename(newPath, app.AppImagePath); err != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("failed to replace AppImage: %w", err)
+	}
+
+	return nil
+}
+
+// upgradeReportEntry is one app's update status, for --json output.
+type upgradeReportEntry struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Outdated bool   `json:"outdated"`
+	Latest   string `json:"latest,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// runUpgradeCheck powers `teabag upgrade --dry-run`, `--json`, and
+// `--apply`: it checks every installed app and, only when apply is set,
+// downloads replacements for anything outdated. When jsonOut is set it
+// prints the JSON report and returns without downloading anything, even
+// if apply is set too.
+func runUpgradeCheck(dryRun, jsonOut, apply bool) error {
+	path, err := manifestPath()
+	if err != nil {
+		return err
+	}
+
+	mf, err := manifest.Load(path)
+	if err != nil {
+		return err
+	}
+
+	statuses := checkForUpdates(mf.Sorted())
+
+	if jsonOut {
+		return printUpgradeReport(statuses)
+	}
+
+	outdated := 0
+	for _, status := range statuses {
+		if status.Err != nil {
+			fmt.Printf("%s: %v\n", status.App.ID, status.Err)
+			continue
+		}
+		if status.Result.Outdated {
+			outdated++
+			fmt.Printf("%s\t%s\t-> %s\n", status.App.ID, status.App.Name, status.Result.LatestName)
+		}
+	}
+
+	if outdated == 0 {
+		fmt.Println(successStyle.Render("✓ Everything is up to date"))
+		return nil
+	}
+
+	if !apply {
+		if dryRun {
+			fmt.Printf("\n%d app(s) have updates available (dry run, nothing downloaded)\n", outdated)
+		} else {
+			fmt.Printf("\n%d app(s) have updates available (use --apply to install them)\n", outdated)
+		}
+		return nil
+	}
+
+	for _, status := range statuses {
+		if status.Err != nil || !status.Result.Outdated {
+			continue
+		}
+		if err := downloadReplacement(status.App, status.Result); err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("✗ %s: %v", status.App.ID, err)))
+			continue
+		}
+		fmt.Println(successStyle.Render(fmt.Sprintf("✓ Updated %s", status.App.ID)))
+	}
+
+	return nil
+}
+
+func printUpgradeReport(statuses []appUpdateStatus) error {
+	entries := make([]upgradeReportEntry, 0, len(statuses))
+	for _, status := range statuses {
+		entry := upgradeReportEntry{ID: status.App.ID, Name: status.App.Name}
+		if status.Err != nil {
+			entry.Error = status.Err.Error()
+		} else {
+			entry.Outdated = status.Result.Outdated
+			entry.Latest = status.Result.LatestName
+		}
+		entries = append(entries, entry)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}